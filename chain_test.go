@@ -0,0 +1,50 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeChainNoCertificates(t *testing.T) {
+	result := analyzeChain("example.com:443", "", tls.ConnectionState{})
+	if result.Err == nil {
+		t.Fatal("expected an error when no certificates are presented")
+	}
+}
+
+func TestAnalyzeChainReportsMinimumAcrossChain(t *testing.T) {
+	leaf := &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "leaf.example.com"},
+		Issuer:    pkix.Name{CommonName: "intermediate CA"},
+		DNSNames:  []string{"leaf.example.com"},
+		NotBefore: time.Now().Add(-24 * time.Hour),
+		NotAfter:  time.Now().Add(60 * 24 * time.Hour),
+	}
+	intermediate := &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "intermediate CA"},
+		NotBefore: time.Now().Add(-24 * time.Hour),
+		NotAfter:  time.Now().Add(5 * 24 * time.Hour), // expires sooner than the leaf
+	}
+
+	state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, intermediate}}
+	result := analyzeChain("example.com:443", "203.0.113.1:443", state)
+
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %v", result.Err)
+	}
+	if len(result.Chain) != 2 {
+		t.Fatalf("expected a 2-entry chain, got %d", len(result.Chain))
+	}
+	if result.DaysRemaining > 5 {
+		t.Fatalf("expected DaysRemaining to reflect the soonest-expiring cert (~5 days), got %d", result.DaysRemaining)
+	}
+	if result.ChainValid {
+		t.Fatal("expected ChainValid to be false without any VerifiedChains")
+	}
+	if result.Subject != leaf.Subject.String() {
+		t.Fatalf("expected Subject %q, got %q", leaf.Subject.String(), result.Subject)
+	}
+}
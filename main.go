@@ -12,7 +12,9 @@ import (
 	"io"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -24,6 +26,16 @@ type Result struct {
 	Endpoint      string
 	DaysRemaining int
 	Err           error
+	Target        Target
+	Source        CertSource
+	Chain         []ChainEntry
+	ChainValid    bool
+	ResolvedIP    string
+	Subject       string
+	Issuer        string
+	SANs          []string
+	NotBefore     time.Time
+	NotAfter      time.Time
 }
 
 // GetDaysRemaining retrieves the number of days remaining until the expiration of the TLS certificate of a given endpoint.
@@ -34,32 +46,38 @@ func GetDaysRemaining(ctx context.Context, endpoint string) Result {
 
 	// Perform TLS operations in a Goroutine
 	go func() {
-		parts := strings.Split(endpoint, ":")
-		if len(parts) != 2 {
+		dialCtx, dialSpan := tracer.Start(ctx, "dial")
+		dialStart := time.Now()
+
+		host, _, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			dialSpan.End()
 			resultChan <- Result{Endpoint: endpoint, Err: fmt.Errorf("invalid endpoint format, expected hostname:port")}
 			return
 		}
 
+		tlsConfig, err := buildTLSConfig(host)
+		if err != nil {
+			dialSpan.End()
+			resultChan <- Result{Endpoint: endpoint, Err: err}
+			return
+		}
+
 		conn, err := tls.DialWithDialer(&net.Dialer{
 			Timeout: 10 * time.Second, // add a TLS dial timeout
-		}, "tcp", endpoint, &tls.Config{
-			InsecureSkipVerify: true,
-		})
+		}, "tcp", endpoint, tlsConfig)
+		dialSpan.End()
+		recordDialDuration(ctx, time.Since(dialStart).Seconds())
 		if err != nil {
 			resultChan <- Result{Endpoint: endpoint, Err: fmt.Errorf("failed to connect to '%s': %v", endpoint, err)}
 			return
 		}
 		defer conn.Close()
 
-		certs := conn.ConnectionState().PeerCertificates
-		if len(certs) == 0 {
-			resultChan <- Result{Endpoint: endpoint, Err: fmt.Errorf("no certificate found for endpoint '%s'", endpoint)}
-			return
-		}
+		_, parseSpan := tracer.Start(dialCtx, "parse")
+		defer parseSpan.End()
 
-		cert := certs[0]
-		daysRemaining := int(time.Until(cert.NotAfter).Hours() / 24)
-		resultChan <- Result{Endpoint: endpoint, DaysRemaining: daysRemaining}
+		resultChan <- analyzeChain(endpoint, conn.RemoteAddr().String(), conn.ConnectionState())
 	}()
 
 	select {
@@ -90,13 +108,15 @@ func createMonitoringClient() (monitoring.MonitoringClient, error) {
 }
 
 // publishMetricData sends metric data to the OCI Monitoring service using the provided MonitoringClient instance.
-func publishMetricData(client monitoring.MonitoringClient, namespace, compartmentID, metricName, resourceID string, value float64) error {
+// dimensions is merged into the metric as-is, letting callers attach per-target metadata (compartment, resource
+// OCID, load balancer name, ...) discovered by a Source.
+func publishMetricData(client monitoring.MonitoringClient, namespace, compartmentID, metricName string, dimensions map[string]string, value float64) error {
 	timestamp := common.SDKTime{Time: time.Now().UTC()}
 	metricData := monitoring.MetricDataDetails{
 		Namespace:     common.String(namespace),
 		CompartmentId: common.String(compartmentID),
 		Name:          common.String(metricName),
-		Dimensions:    map[string]string{"resourceId": resourceID},
+		Dimensions:    dimensions,
 		Datapoints: []monitoring.Datapoint{
 			{
 				Timestamp: &timestamp,
@@ -151,54 +171,144 @@ func getCompartmentID(ctx context.Context) (string, error) {
 	return *response.CompartmentId, nil
 }
 
+// buildSources composes the Sources a deployment should use from its environment. DISCOVERY_SOURCES is a
+// comma-separated list of "static", "loadbalancer", "apigateway" and "oke"; it defaults to "static" so existing
+// ENDPOINTS-based deployments keep working unchanged.
+func buildSources(compartmentID string) []Source {
+	kinds := os.Getenv("DISCOVERY_SOURCES")
+	if kinds == "" {
+		kinds = "static"
+	}
+
+	var sources []Source
+	for _, kind := range strings.Split(kinds, ",") {
+		switch strings.TrimSpace(kind) {
+		case "static":
+			sources = append(sources, StaticSource{Endpoints: os.Getenv("ENDPOINTS")})
+		case "loadbalancer":
+			sources = append(sources, LoadBalancerSource{CompartmentID: compartmentID})
+		case "apigateway":
+			sources = append(sources, APIGatewaySource{CompartmentID: compartmentID})
+		case "oke":
+			sources = append(sources, OKEIngressSource{})
+		}
+	}
+	return sources
+}
+
+// resultDimensions builds the OCI Monitoring dimension set for a Result, including only the metadata that was
+// actually populated by the Source that produced it, plus a "source" dimension distinguishing a live TLS dial
+// from a managed certificate resource that was inspected directly.
+func resultDimensions(result Result) map[string]string {
+	dimensions := map[string]string{"resourceId": result.Endpoint, "source": string(result.Source)}
+	if result.Target.Compartment != "" {
+		dimensions["compartment"] = result.Target.Compartment
+	}
+	if result.Target.ResourceID != "" {
+		dimensions["resourceOcid"] = result.Target.ResourceID
+	}
+	if result.Target.LBName != "" {
+		dimensions["lbName"] = result.Target.LBName
+	}
+	return dimensions
+}
+
 func main() {
+	// Telemetry is initialized exactly once at process start, not per invocation: serveHTTP handles requests
+	// concurrently, and re-initializing per request would race on the package-level tracer/meter and leak an
+	// OTLP connection on every call.
+	shutdownTelemetry, err := initTelemetry(context.Background())
+	if err != nil {
+		log.Printf("Failed to initialize OpenTelemetry: %v", err)
+		shutdownTelemetry = func(context.Context) error { return nil }
+	}
+	defer func() {
+		if err := shutdownTelemetry(context.Background()); err != nil {
+			log.Printf("Failed to shut down OpenTelemetry: %v", err)
+		}
+	}()
+
+	// Running with SERVER_ADDR set turns the binary into a standalone HTTP server exposing /metrics in
+	// Prometheus format, so it can be deployed as a sidecar or scrape target instead of only an OCI Function.
+	if addr := os.Getenv("SERVER_ADDR"); addr != "" {
+		serveHTTP(addr)
+		return
+	}
+
 	fdk.Handle(fdk.HandlerFunc(func(ctx context.Context, in io.Reader, out io.Writer) {
-		// Read environment variables
-		endpoints := os.Getenv("ENDPOINTS")
-		namespace := os.Getenv("NAMESPACE")
-		metricName := os.Getenv("METRIC_NAME")
+		if err := runCheckCycle(ctx, out, os.Getenv("OUTPUT_FORMAT")); err != nil {
+			log.Printf("Check cycle failed: %v", err)
+		}
+	}))
+}
 
-		if endpoints == "" || namespace == "" || metricName == "" {
-			log.Fatalf("One or more required environment variables are missing (ENDPOINT, NAMESPACE, METRIC_NAME)")
+// serveHTTP runs the check cycle synchronously on every GET /metrics request and writes the result in
+// Prometheus text exposition format, so the binary can be scraped directly as a sidecar or standalone server.
+func serveHTTP(addr string) {
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := runCheckCycle(r.Context(), w, "prometheus"); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
+	})
+	log.Printf("Serving %s/metrics", addr)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
+
+// runCheckCycle discovers targets, checks every certificate (TLS dial, and optionally the Certificates
+// service and Vault secrets), publishes a metric per result when OCI Monitoring is configured, and writes the
+// aggregated Report set to out in the requested format ("json", "prometheus", or the plain-text default).
+// NAMESPACE/METRIC_NAME (and the OCI Monitoring client/compartment lookup they gate) are only required when
+// publishing metrics; a standalone deployment (SERVER_ADDR) can run with neither set and still discover,
+// check, and report certificates.
+func runCheckCycle(ctx context.Context, out io.Writer, format string) error {
+	namespace := os.Getenv("NAMESPACE")
+	metricName := os.Getenv("METRIC_NAME")
+	publishMetrics := namespace != "" && metricName != ""
 
-		// Initialize OCI monitoring client
-		client, err := createMonitoringClient()
+	var client monitoring.MonitoringClient
+	var compartmentID string
+	if publishMetrics {
+		var err error
+		client, err = createMonitoringClient()
 		if err != nil {
-			log.Printf("Failed to create monitoring client: %v", err)
-			return
+			return fmt.Errorf("failed to create monitoring client: %v", err)
 		}
-
-		// Retrieve compartment ID (OCI context dependency)
-		compartmentID, err := getCompartmentID(ctx)
+		compartmentID, err = getCompartmentID(ctx)
 		if err != nil {
-			log.Printf("Failed to retrieve compartment ID: %v", err)
-			return
+			return fmt.Errorf("failed to retrieve compartment ID: %v", err)
 		}
+	} else {
+		// Best-effort only: some discovery sources (loadbalancer, apigateway) want a compartment ID even
+		// when metrics aren't being published, but its absence shouldn't stop a standalone deployment.
+		compartmentID, _ = getCompartmentID(ctx)
+	}
 
-		// Split endpoints into a slice
-		endpointList := strings.Split(endpoints, ",")
-		results := make(chan Result, len(endpointList)) // Channel to collect results
-		var wg sync.WaitGroup
+	// Discover targets across every configured source
+	targets := DiscoverAll(ctx, buildSources(compartmentID))
+	retryConfig := loadRetryConfig()
+	var allResults []Result
 
-		// Process each endpoint concurrently
-		for _, endpoint := range endpointList {
-			if !strings.Contains(endpoint, ":") {
-				endpoint = endpoint + ":443" // Ensure default port 443
-			}
+	if len(targets) > 0 {
+		results := make(chan Result, len(targets)) // Channel to collect results
+		var wg sync.WaitGroup
 
+		// Process each target concurrently
+		for _, target := range targets {
 			wg.Add(1)
-			go func(endpoint string) {
+			go func(target Target) {
 				defer wg.Done()
 
 				// Set up timeout context per endpoint
 				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 				defer cancel()
 
-				// Get days remaining and send the Result to the channel
-				result := GetDaysRemaining(ctx, endpoint)
+				// Get days remaining (with retry/backoff and a per-endpoint circuit breaker) and send the
+				// Result to the channel
+				result := CheckEndpointResilient(ctx, retryConfig, target.Endpoint)
+				result.Target = target
 				results <- result
-			}(endpoint)
+			}(target)
 		}
 
 		// Close results channel after all workers finish
@@ -207,20 +317,107 @@ func main() {
 			close(results)
 		}()
 
-		// Collect and log results
 		for result := range results {
-			if result.Err != nil {
-				log.Printf("Failed to process endpoint: %s, Error: %v", result.Endpoint, result.Err)
-				_, _ = fmt.Fprintf(out, "Failed to process endpoint: %s, Error: %v\n", result.Endpoint, result.Err)
-			} else {
-				log.Printf("Days remaining for %s: %d days", result.Endpoint, result.DaysRemaining)
-				_, _ = fmt.Fprintf(out, "Successfully processed endpoint: %s, Days Remaining: %d\n", result.Endpoint, result.DaysRemaining)
-				// Optionally publish the metric
-				err = publishMetricData(client, namespace, compartmentID, metricName, result.Endpoint, float64(result.DaysRemaining))
-				if err != nil {
-					log.Printf("Failed to publish metric for %s: %v", result.Endpoint, err)
-				}
+			allResults = append(allResults, result)
+		}
+	}
+
+	// Managed certificates don't need a network dial: check the Certificates service and Vault secrets
+	// directly and report them under the same namespace with a distinguishing source dimension.
+	if os.Getenv("CHECK_CERTIFICATES_SERVICE") == "true" {
+		certResults, err := CheckCertificatesService(ctx, compartmentID)
+		if err != nil {
+			log.Printf("Failed to check Certificates service: %v", err)
+		}
+		allResults = append(allResults, certResults...)
+	}
+
+	if secretIDs := os.Getenv("VAULT_SECRET_IDS"); secretIDs != "" {
+		var trimmedSecretIDs []string
+		for _, id := range strings.Split(secretIDs, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				trimmedSecretIDs = append(trimmedSecretIDs, id)
 			}
 		}
-	}))
+		vaultResults, err := CheckVaultSecrets(ctx, compartmentID, trimmedSecretIDs)
+		if err != nil {
+			log.Printf("Failed to check Vault secrets: %v", err)
+		}
+		allResults = append(allResults, vaultResults...)
+	}
+
+	reports := make([]Report, 0, len(allResults))
+	for _, result := range allResults {
+		logResult(result)
+		if publishMetrics {
+			publishResult(ctx, client, retryConfig, namespace, compartmentID, metricName, result)
+		}
+		reports = append(reports, NewReport(result))
+	}
+
+	formatted, err := FormatReports(reports, format)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprint(out, formatted)
+	return nil
+}
+
+// certCheckFailedMetric is published in place of the regular days-remaining gauge whenever a certificate
+// couldn't be checked at all, so alerts can distinguish "cert expiring" from "endpoint unreachable".
+const certCheckFailedMetric = "certificate_check_failed"
+
+// chainValidMetric carries a 1/0 value reporting whether a TLS-dial Result's chain verified against the
+// configured roots (see buildTLSConfig); it is only published for TLS-dial results, since chain verification
+// doesn't apply to certs read directly from the Certificates service or Vault.
+const chainValidMetric = "certificate_chain_valid"
+
+// logResult logs a Result's outcome and records it against the check-outcome counter; it runs for every
+// Result regardless of whether metrics are published to OCI Monitoring.
+func logResult(result Result) {
+	if result.Err != nil {
+		log.Printf("Failed to process endpoint: %s, Error: %v", result.Endpoint, result.Err)
+		recordOutcome(context.Background(), classifyFailure(result.Err))
+		return
+	}
+	log.Printf("Days remaining for %s: %d days", result.Endpoint, result.DaysRemaining)
+	recordOutcome(context.Background(), "ok")
+}
+
+// publishResult publishes a Result as a metric: the regular days-remaining gauge on success, or a
+// certificate_check_failed gauge carrying a failure-reason dimension when the certificate couldn't be
+// checked at all.
+func publishResult(ctx context.Context, client monitoring.MonitoringClient, retryConfig RetryConfig, namespace, compartmentID, metricName string, result Result) {
+	if result.Err != nil {
+		dimensions := resultDimensions(result)
+		dimensions["reason"] = classifyFailure(result.Err)
+		if err := publishMetricDataResilient(ctx, retryConfig, client, namespace, compartmentID, certCheckFailedMetric, dimensions, 1); err != nil {
+			log.Printf("Failed to publish failure metric for %s: %v", result.Endpoint, err)
+		}
+		return
+	}
+
+	if err := publishMetricDataResilient(ctx, retryConfig, client, namespace, compartmentID, metricName, resultDimensions(result), float64(result.DaysRemaining)); err != nil {
+		log.Printf("Failed to publish metric for %s: %v", result.Endpoint, err)
+	}
+
+	if result.Source != SourceTLSDial {
+		return
+	}
+
+	chainValid := 0.0
+	if result.ChainValid {
+		chainValid = 1.0
+	}
+	if err := publishMetricDataResilient(ctx, retryConfig, client, namespace, compartmentID, chainValidMetric, resultDimensions(result), chainValid); err != nil {
+		log.Printf("Failed to publish chain-valid metric for %s: %v", result.Endpoint, err)
+	}
+
+	for _, entry := range result.Chain {
+		dimensions := resultDimensions(result)
+		dimensions["chain_depth"] = strconv.Itoa(entry.Depth)
+		if err := publishMetricDataResilient(ctx, retryConfig, client, namespace, compartmentID, metricName, dimensions, float64(entry.DaysRemaining)); err != nil {
+			log.Printf("Failed to publish chain-depth metric for %s (depth %d): %v", result.Endpoint, entry.Depth, err)
+		}
+	}
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Report is the stable, serializable view of a Result: everything downstream tooling (a JSON consumer, a
+// Prometheus scraper, or a human reading function logs) needs to know about one checked certificate.
+type Report struct {
+	Endpoint      string     `json:"endpoint"`
+	ResolvedIP    string     `json:"resolvedIp,omitempty"`
+	Subject       string     `json:"subject,omitempty"`
+	Issuer        string     `json:"issuer,omitempty"`
+	SANs          []string   `json:"sans,omitempty"`
+	NotBefore     *time.Time `json:"notBefore,omitempty"`
+	NotAfter      *time.Time `json:"notAfter,omitempty"`
+	DaysRemaining int        `json:"daysRemaining"`
+	ChainLength   int        `json:"chainLength,omitempty"`
+	ChainValid    *bool      `json:"chainValid,omitempty"`
+	Source        string     `json:"source,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// NewReport converts a Result into its serializable Report form. NotBefore/NotAfter/ChainValid use pointers
+// so that an unset time (a managed-certificate Result has no NotBefore) or an inapplicable chain-validity
+// (only TLS-dial Results verify a chain) are genuinely omitted from JSON, while an applicable
+// ChainValid: false still renders explicitly instead of vanishing behind omitempty's zero-value check.
+func NewReport(result Result) Report {
+	report := Report{
+		Endpoint:      result.Endpoint,
+		ResolvedIP:    result.ResolvedIP,
+		Subject:       result.Subject,
+		Issuer:        result.Issuer,
+		SANs:          result.SANs,
+		DaysRemaining: result.DaysRemaining,
+		ChainLength:   len(result.Chain),
+		Source:        string(result.Source),
+	}
+	if !result.NotBefore.IsZero() {
+		notBefore := result.NotBefore
+		report.NotBefore = &notBefore
+	}
+	if !result.NotAfter.IsZero() {
+		notAfter := result.NotAfter
+		report.NotAfter = &notAfter
+	}
+	if result.Source == SourceTLSDial {
+		chainValid := result.ChainValid
+		report.ChainValid = &chainValid
+	}
+	if result.Err != nil {
+		report.Error = result.Err.Error()
+	}
+	return report
+}
+
+// FormatReports renders reports in the OUTPUT_FORMAT requested: "json", "prometheus", or the plain-text
+// format the tool has always printed, which remains the default.
+func FormatReports(reports []Report, format string) (string, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal reports: %v", err)
+		}
+		return string(data) + "\n", nil
+	case "prometheus":
+		return formatPrometheus(reports), nil
+	default:
+		return formatText(reports), nil
+	}
+}
+
+// formatPrometheus renders the days-remaining gauge in Prometheus text exposition format so the binary can
+// be scraped directly when run as a sidecar or standalone HTTP server.
+func formatPrometheus(reports []Report) string {
+	var b strings.Builder
+	b.WriteString("# HELP cert_expiry_days Days remaining until certificate expiry\n")
+	b.WriteString("# TYPE cert_expiry_days gauge\n")
+	for _, report := range reports {
+		if report.Error != "" {
+			continue
+		}
+		fmt.Fprintf(&b, "cert_expiry_days{endpoint=%q,issuer=%q} %d\n", report.Endpoint, report.Issuer, report.DaysRemaining)
+	}
+	return b.String()
+}
+
+// formatText renders reports in the original ad-hoc log-line format.
+func formatText(reports []Report) string {
+	var b strings.Builder
+	for _, report := range reports {
+		if report.Error != "" {
+			fmt.Fprintf(&b, "Failed to process endpoint: %s, Error: %s\n", report.Endpoint, report.Error)
+			continue
+		}
+		fmt.Fprintf(&b, "Successfully processed endpoint: %s, Days Remaining: %d\n", report.Endpoint, report.DaysRemaining)
+	}
+	return b.String()
+}
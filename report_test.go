@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFormatReportsText(t *testing.T) {
+	reports := []Report{
+		{Endpoint: "ok.example.com:443", DaysRemaining: 30},
+		{Endpoint: "bad.example.com:443", Error: "boom"},
+	}
+	out, err := FormatReports(reports, "text")
+	if err != nil {
+		t.Fatalf("FormatReports: %v", err)
+	}
+	if !strings.Contains(out, "Successfully processed endpoint: ok.example.com:443, Days Remaining: 30") {
+		t.Errorf("text output missing success line: %q", out)
+	}
+	if !strings.Contains(out, "Failed to process endpoint: bad.example.com:443, Error: boom") {
+		t.Errorf("text output missing failure line: %q", out)
+	}
+}
+
+func TestFormatReportsPrometheusSkipsErrors(t *testing.T) {
+	reports := []Report{
+		{Endpoint: "ok.example.com:443", Issuer: "Let's Encrypt", DaysRemaining: 30},
+		{Endpoint: "bad.example.com:443", Error: "boom"},
+	}
+	out, err := FormatReports(reports, "prometheus")
+	if err != nil {
+		t.Fatalf("FormatReports: %v", err)
+	}
+	if !strings.Contains(out, `cert_expiry_days{endpoint="ok.example.com:443",issuer="Let's Encrypt"} 30`) {
+		t.Errorf("prometheus output missing gauge line: %q", out)
+	}
+	if strings.Contains(out, "bad.example.com") {
+		t.Errorf("prometheus output should skip errored endpoints: %q", out)
+	}
+}
+
+func TestFormatReportsJSON(t *testing.T) {
+	reports := []Report{{Endpoint: "ok.example.com:443", DaysRemaining: 30}}
+	out, err := FormatReports(reports, "json")
+	if err != nil {
+		t.Fatalf("FormatReports: %v", err)
+	}
+	var decoded []Report
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(decoded) != 1 || decoded[0].Endpoint != "ok.example.com:443" {
+		t.Fatalf("unexpected decoded reports: %+v", decoded)
+	}
+}
+
+func TestNewReportOmitsInapplicableFields(t *testing.T) {
+	managed := NewReport(Result{Endpoint: "my-cert", DaysRemaining: 10, Source: SourceCertService})
+	if managed.NotBefore != nil || managed.NotAfter != nil {
+		t.Errorf("expected NotBefore/NotAfter to stay nil for a managed-certificate Result, got %+v", managed)
+	}
+	if managed.ChainValid != nil {
+		t.Errorf("expected ChainValid to stay nil for a non-TLS-dial Result, got %v", *managed.ChainValid)
+	}
+
+	dialed := NewReport(Result{Endpoint: "example.com:443", DaysRemaining: 10, Source: SourceTLSDial, ChainValid: false})
+	if dialed.ChainValid == nil || *dialed.ChainValid != false {
+		t.Errorf("expected ChainValid to be explicitly false for a TLS-dial Result, got %+v", dialed.ChainValid)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/monitoring"
+	"github.com/sony/gobreaker"
+)
+
+// RetryConfig controls the retry/backoff loop wrapped around flaky operations like a TLS dial or a metric
+// publish. Values are sourced from env vars so a deployment can tune behavior per environment without a
+// redeploy, mirroring how endpoints and thresholds are already configured.
+type RetryConfig struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Timeout      time.Duration
+	Jitter       bool
+}
+
+// loadRetryConfig reads RETRY_* env vars, falling back to conservative defaults.
+func loadRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:  envInt("RETRY_MAX_ATTEMPTS", 3),
+		InitialDelay: envDuration("RETRY_INITIAL_DELAY", 500*time.Millisecond),
+		MaxDelay:     envDuration("RETRY_MAX_DELAY", 5*time.Second),
+		Timeout:      envDuration("RETRY_TIMEOUT", 10*time.Second),
+		Jitter:       os.Getenv("RETRY_JITTER") != "false",
+	}
+}
+
+func envInt(name string, fallback int) int {
+	if v := os.Getenv(name); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func envDuration(name string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(name); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+// withRetry runs fn up to cfg.MaxAttempts times, backing off exponentially between attempts (with optional
+// jitter) and giving up early once ctx is done or the overall cfg.Timeout ceiling is reached.
+func withRetry(ctx context.Context, cfg RetryConfig, fn func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	delay := cfg.InitialDelay
+	var err error
+	for attempt := 1; attempt <= cfg.MaxAttempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt == cfg.MaxAttempts {
+			break
+		}
+
+		wait := delay
+		if cfg.Jitter {
+			wait += time.Duration(rand.Int63n(int64(delay) + 1))
+		}
+		if wait > cfg.MaxDelay {
+			wait = cfg.MaxDelay
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if delay > cfg.MaxDelay {
+			delay = cfg.MaxDelay
+		}
+	}
+	return err
+}
+
+var (
+	dialBreakersMu sync.Mutex
+	dialBreakers   = map[string]*gobreaker.CircuitBreaker{}
+	publishBreaker = gobreaker.NewCircuitBreaker(gobreaker.Settings{Name: "publish-metric"})
+)
+
+// dialBreakerFor returns the circuit breaker for an endpoint, creating one on first use so a single
+// persistently-unreachable endpoint trips independently of the others.
+func dialBreakerFor(endpoint string) *gobreaker.CircuitBreaker {
+	dialBreakersMu.Lock()
+	defer dialBreakersMu.Unlock()
+	if breaker, ok := dialBreakers[endpoint]; ok {
+		return breaker
+	}
+	breaker := gobreaker.NewCircuitBreaker(gobreaker.Settings{Name: "dial-" + endpoint})
+	dialBreakers[endpoint] = breaker
+	return breaker
+}
+
+// CheckEndpointResilient wraps GetDaysRemaining with a per-endpoint circuit breaker and retry/backoff loop so a
+// single endpoint's failures don't waste every retry budget on a dead target.
+func CheckEndpointResilient(ctx context.Context, cfg RetryConfig, endpoint string) Result {
+	breaker := dialBreakerFor(endpoint)
+	var result Result
+
+	err := withRetry(ctx, cfg, func() error {
+		value, err := breaker.Execute(func() (interface{}, error) {
+			r := GetDaysRemaining(ctx, endpoint)
+			if r.Err != nil {
+				return nil, r.Err
+			}
+			return r, nil
+		})
+		if err != nil {
+			return err
+		}
+		result = value.(Result)
+		return nil
+	})
+	if err != nil {
+		return Result{Endpoint: endpoint, Err: err, Source: SourceTLSDial}
+	}
+	return result
+}
+
+// publishMetricDataResilient wraps publishMetricData with a shared circuit breaker and retry/backoff loop so a
+// transient Monitoring outage doesn't drop every metric in the batch.
+func publishMetricDataResilient(ctx context.Context, cfg RetryConfig, client monitoring.MonitoringClient, namespace, compartmentID, metricName string, dimensions map[string]string, value float64) error {
+	_, span := tracer.Start(ctx, "publish")
+	defer span.End()
+
+	return withRetry(ctx, cfg, func() error {
+		_, err := publishBreaker.Execute(func() (interface{}, error) {
+			return nil, publishMetricData(client, namespace, compartmentID, metricName, dimensions, value)
+		})
+		return err
+	})
+}
+
+// classifyFailure maps a failure into a coarse failure-reason dimension so alerts can distinguish "cert
+// expiring" from "endpoint unreachable" and the flavor of unreachability.
+func classifyFailure(err error) string {
+	if err == nil {
+		return ""
+	}
+	if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timeout") {
+		return "dial timeout"
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "certificate") && strings.Contains(msg, "parse"):
+		return "cert parse error"
+	case strings.Contains(msg, "handshake") || strings.Contains(msg, "tls"):
+		return "handshake error"
+	case strings.Contains(msg, "connect"):
+		return "dial timeout"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"deadline exceeded", context.DeadlineExceeded, "dial timeout"},
+		{"timeout text", errors.New("i/o timeout"), "dial timeout"},
+		{"handshake", errors.New("tls: handshake failure"), "handshake error"},
+		{"cert parse", errors.New("failed to parse certificate: asn1 error"), "cert parse error"},
+		{"connect refused", errors.New("dial tcp: connect: connection refused"), "dial timeout"},
+		{"unknown", errors.New("boom"), "unknown"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := classifyFailure(tc.err); got != tc.want {
+				t.Errorf("classifyFailure(%v) = %q, want %q", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithRetrySucceedsEventually(t *testing.T) {
+	attempts := 0
+	cfg := RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Timeout: time.Second, Jitter: false}
+
+	err := withRetry(context.Background(), cfg, func() error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("withRetry returned error: %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestWithRetryExhaustsAttempts(t *testing.T) {
+	attempts := 0
+	cfg := RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Timeout: time.Second, Jitter: false}
+
+	err := withRetry(context.Background(), cfg, func() error {
+		attempts++
+		return errors.New("permanent")
+	})
+
+	if err == nil {
+		t.Fatal("expected withRetry to return an error")
+	}
+	if attempts != cfg.MaxAttempts {
+		t.Fatalf("expected %d attempts, got %d", cfg.MaxAttempts, attempts)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/apigateway"
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
+	"github.com/oracle/oci-go-sdk/v65/loadbalancer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Target describes a single certificate-bearing endpoint to monitor, along
+// with the metadata of the OCI (or Kubernetes) resource it was discovered
+// from. The metadata flows through to the metrics published for the target
+// so that an expiring certificate can be traced back to the resource that
+// owns it without hand-maintaining a lookup table.
+type Target struct {
+	Endpoint    string
+	Compartment string
+	ResourceID  string
+	LBName      string
+}
+
+// Source discovers the set of Targets that should be checked for
+// certificate expiration. A deployment composes one or more Sources so a
+// single invocation can cover an entire tenancy instead of a hand-maintained
+// endpoint list.
+type Source interface {
+	Discover(ctx context.Context) ([]Target, error)
+}
+
+// StaticSource returns the fixed list of endpoints parsed from a
+// comma-separated string, preserving the original ENDPOINTS env-var
+// behavior for users who don't need live discovery.
+type StaticSource struct {
+	Endpoints string
+}
+
+// Discover splits the configured endpoint string into Targets, defaulting
+// to port 443 when none is specified.
+func (s StaticSource) Discover(ctx context.Context) ([]Target, error) {
+	var targets []Target
+	for _, endpoint := range strings.Split(s.Endpoints, ",") {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint == "" {
+			continue
+		}
+		if !strings.Contains(endpoint, ":") {
+			endpoint = endpoint + ":443"
+		}
+		targets = append(targets, Target{Endpoint: endpoint})
+	}
+	return targets, nil
+}
+
+// LoadBalancerSource discovers HTTPS listener endpoints on OCI Load
+// Balancers within a compartment.
+type LoadBalancerSource struct {
+	CompartmentID string
+}
+
+// Discover lists the load balancers in the configured compartment and
+// returns a Target for every HTTPS listener found on them.
+func (s LoadBalancerSource) Discover(ctx context.Context) ([]Target, error) {
+	provider, err := auth.ResourcePrincipalConfigurationProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Resource Principal provider: %v", err)
+	}
+	client, err := loadbalancer.NewLoadBalancerClientWithConfigurationProvider(provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create load balancer client: %v", err)
+	}
+
+	response, err := client.ListLoadBalancers(ctx, loadbalancer.ListLoadBalancersRequest{
+		CompartmentId: &s.CompartmentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list load balancers: %v", err)
+	}
+
+	var targets []Target
+	for _, lb := range response.Items {
+		if len(lb.IpAddresses) == 0 || lb.IpAddresses[0].IpAddress == nil || lb.Id == nil || lb.DisplayName == nil {
+			continue
+		}
+		for _, listener := range lb.Listeners {
+			if listener.Protocol == nil || !strings.EqualFold(*listener.Protocol, "HTTPS") || listener.Port == nil {
+				continue
+			}
+			targets = append(targets, Target{
+				Endpoint:    fmt.Sprintf("%s:%d", *lb.IpAddresses[0].IpAddress, *listener.Port),
+				Compartment: s.CompartmentID,
+				ResourceID:  *lb.Id,
+				LBName:      *lb.DisplayName,
+			})
+		}
+	}
+	return targets, nil
+}
+
+// APIGatewaySource discovers the custom domain hostnames fronting OCI API
+// Gateway deployments within a compartment.
+type APIGatewaySource struct {
+	CompartmentID string
+}
+
+// Discover lists the gateways in the configured compartment and returns a
+// Target for each one that has a hostname configured.
+func (s APIGatewaySource) Discover(ctx context.Context) ([]Target, error) {
+	provider, err := auth.ResourcePrincipalConfigurationProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Resource Principal provider: %v", err)
+	}
+	client, err := apigateway.NewGatewayClientWithConfigurationProvider(provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API Gateway client: %v", err)
+	}
+
+	response, err := client.ListGateways(ctx, apigateway.ListGatewaysRequest{
+		CompartmentId: &s.CompartmentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API gateways: %v", err)
+	}
+
+	var targets []Target
+	for _, gw := range response.Items {
+		if gw.Hostname == nil || gw.Id == nil || gw.DisplayName == nil {
+			continue
+		}
+		targets = append(targets, Target{
+			Endpoint:    *gw.Hostname + ":443",
+			Compartment: s.CompartmentID,
+			ResourceID:  *gw.Id,
+			LBName:      *gw.DisplayName,
+		})
+	}
+	return targets, nil
+}
+
+// OKEIngressSource discovers TLS hosts declared on Kubernetes Ingress
+// resources of an OKE cluster, using an in-cluster config when available
+// and falling back to the kubeconfig pointed to by the KUBECONFIG env var.
+type OKEIngressSource struct {
+	Kubeconfig string
+}
+
+// Discover lists Ingress resources across all namespaces and returns a
+// Target for every host listed under spec.tls.
+func (s OKEIngressSource) Discover(ctx context.Context) ([]Target, error) {
+	config, err := s.restConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Kubernetes client config: %v", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %v", err)
+	}
+
+	ingresses, err := clientset.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %v", err)
+	}
+
+	var targets []Target
+	for _, ing := range ingresses.Items {
+		for _, tls := range ing.Spec.TLS {
+			for _, host := range tls.Hosts {
+				targets = append(targets, Target{
+					Endpoint:   host + ":443",
+					ResourceID: string(ing.UID),
+					LBName:     ing.Name,
+				})
+			}
+		}
+	}
+	return targets, nil
+}
+
+func (s OKEIngressSource) restConfig() (*rest.Config, error) {
+	if config, err := rest.InClusterConfig(); err == nil {
+		return config, nil
+	}
+	kubeconfig := s.Kubeconfig
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+	return clientcmd.BuildConfigFromFlags("", kubeconfig)
+}
+
+// DiscoverAll runs every configured Source and merges their Targets,
+// logging (rather than failing) a Source that errors so one misbehaving
+// discovery mechanism doesn't block the rest of a deployment.
+func DiscoverAll(ctx context.Context, sources []Source) []Target {
+	ctx, span := tracer.Start(ctx, "discover")
+	defer span.End()
+
+	var targets []Target
+	for _, source := range sources {
+		discovered, err := source.Discover(ctx)
+		if err != nil {
+			log.Printf("discovery source failed: %v", err)
+			continue
+		}
+		targets = append(targets, discovered...)
+	}
+	return targets
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStaticSourceDiscover(t *testing.T) {
+	s := StaticSource{Endpoints: " example.com , with-port.example.com:8443,,bare.example.com "}
+	targets, err := s.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("Discover returned error: %v", err)
+	}
+
+	want := []Target{
+		{Endpoint: "example.com:443"},
+		{Endpoint: "with-port.example.com:8443"},
+		{Endpoint: "bare.example.com:443"},
+	}
+	if !reflect.DeepEqual(targets, want) {
+		t.Fatalf("Discover(%q) = %+v, want %+v", s.Endpoints, targets, want)
+	}
+}
+
+// fakeSource is a test-only Source that returns a canned result, used to exercise DiscoverAll's
+// merge-and-skip-on-error behavior without depending on any OCI/Kubernetes client.
+type fakeSource struct {
+	targets []Target
+	err     error
+}
+
+func (f fakeSource) Discover(ctx context.Context) ([]Target, error) {
+	return f.targets, f.err
+}
+
+func TestDiscoverAllMergesSources(t *testing.T) {
+	sources := []Source{
+		fakeSource{targets: []Target{{Endpoint: "a.example.com:443"}}},
+		fakeSource{targets: []Target{{Endpoint: "b.example.com:443"}}},
+	}
+	targets := DiscoverAll(context.Background(), sources)
+
+	want := []Target{{Endpoint: "a.example.com:443"}, {Endpoint: "b.example.com:443"}}
+	if !reflect.DeepEqual(targets, want) {
+		t.Fatalf("DiscoverAll = %+v, want %+v", targets, want)
+	}
+}
+
+func TestDiscoverAllSkipsFailingSource(t *testing.T) {
+	sources := []Source{
+		fakeSource{err: errors.New("discovery failed")},
+		fakeSource{targets: []Target{{Endpoint: "ok.example.com:443"}}},
+	}
+	targets := DiscoverAll(context.Background(), sources)
+
+	want := []Target{{Endpoint: "ok.example.com:443"}}
+	if !reflect.DeepEqual(targets, want) {
+		t.Fatalf("DiscoverAll = %+v, want %+v", targets, want)
+	}
+}
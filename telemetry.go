@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// tracer produces the discover/dial/parse/publish spans for each invocation, and meter produces the
+// dial-duration and check-outcome instruments below. Both default to global no-op implementations and are
+// backed by a real exporter once initTelemetry installs the SDK providers; since otel.Tracer/otel.Meter
+// return delegating proxies, it's safe to obtain them here, before initTelemetry runs in main. initTelemetry
+// itself must only ever be called once, from main, since the providers it installs are process-wide.
+var (
+	tracer = otel.Tracer("certificate-expiration-monitoring")
+	meter  = otel.Meter("certificate-expiration-monitoring")
+
+	dialDurationHistogram metric.Float64Histogram
+	checkOutcomeCounter   metric.Int64Counter
+)
+
+func init() {
+	var err error
+	dialDurationHistogram, err = meter.Float64Histogram(
+		"cert_dial_duration_seconds",
+		metric.WithDescription("Duration of the TLS dial phase, in seconds"),
+	)
+	if err != nil {
+		log.Printf("failed to create dial duration instrument: %v", err)
+	}
+
+	checkOutcomeCounter, err = meter.Int64Counter(
+		"cert_checks_total",
+		metric.WithDescription("Number of certificate checks performed, labeled by outcome"),
+	)
+	if err != nil {
+		log.Printf("failed to create check outcome instrument: %v", err)
+	}
+}
+
+// recordDialDuration records how long the TLS dial phase of a check took. It is a no-op if the instrument
+// failed to initialize.
+func recordDialDuration(ctx context.Context, seconds float64) {
+	if dialDurationHistogram == nil {
+		return
+	}
+	dialDurationHistogram.Record(ctx, seconds)
+}
+
+// recordOutcome records a single certificate check against the check-outcome counter, labeled with outcome
+// (e.g. "ok", or a classifyFailure reason). It is a no-op if the instrument failed to initialize.
+func recordOutcome(ctx context.Context, outcome string) {
+	if checkOutcomeCounter == nil {
+		return
+	}
+	checkOutcomeCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", outcome)))
+}
+
+// initTelemetry wires up an OTLP/gRPC trace and metric exporter when OTEL_EXPORTER_OTLP_ENDPOINT is set, so
+// the function's discover/dial/parse/publish phases are visible as spans and its dial-duration/check-outcome
+// metrics can be correlated with downstream alerts even when run outside OCI Functions (e.g. as a CronJob).
+// It must be called exactly once, from main, before any request is served: the tracer/meter it installs are
+// shared package-level state, and re-initializing them per request would race under serveHTTP's concurrent
+// handling and leak an OTLP connection per call. The returned shutdown func must be called before the process
+// exits so buffered telemetry is flushed; it is a no-op when OTel wasn't configured.
+func initTelemetry(ctx context.Context) (func(context.Context) error, error) {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(semconv.ServiceName("certificate-expiration-monitoring")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OTel resource: %v", err)
+	}
+
+	traceExporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %v", err)
+	}
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(traceExporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tracerProvider)
+	tracer = tracerProvider.Tracer("certificate-expiration-monitoring")
+
+	metricExporter, err := otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithEndpoint(endpoint), otlpmetricgrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metric exporter: %v", err)
+	}
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetMeterProvider(meterProvider)
+
+	return func(shutdownCtx context.Context) error {
+		if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		return meterProvider.Shutdown(shutdownCtx)
+	}, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/oracle/oci-go-sdk/v65/certificatesmanagement"
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
+	"github.com/oracle/oci-go-sdk/v65/secrets"
+)
+
+// CertSource identifies where a Result's certificate data came from, so the published metric can carry a
+// source dimension distinguishing a live TLS dial from a managed resource that was inspected directly.
+type CertSource string
+
+const (
+	SourceTLSDial     CertSource = "tls-dial"
+	SourceCertService CertSource = "cert-service"
+	SourceVault       CertSource = "vault"
+)
+
+// CheckCertificatesService enumerates every certificate in the OCI Certificates service for a compartment and
+// computes days-until-expiry from its current version metadata, without dialing anything over the network.
+func CheckCertificatesService(ctx context.Context, compartmentID string) ([]Result, error) {
+	provider, err := auth.ResourcePrincipalConfigurationProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Resource Principal provider: %v", err)
+	}
+	client, err := certificatesmanagement.NewCertificatesManagementClientWithConfigurationProvider(provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Certificates Management client: %v", err)
+	}
+
+	var items []certificatesmanagement.CertificateSummary
+	request := certificatesmanagement.ListCertificatesRequest{CompartmentId: &compartmentID}
+	for {
+		listResp, err := client.ListCertificates(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list certificates: %v", err)
+		}
+		items = append(items, listResp.Items...)
+		if listResp.OpcNextPage == nil {
+			break
+		}
+		request.Page = listResp.OpcNextPage
+	}
+
+	var results []Result
+	for _, summary := range items {
+		if summary.CurrentVersionSummary == nil || summary.CurrentVersionSummary.Validity == nil || summary.CurrentVersionSummary.Validity.TimeOfValidityNotAfter == nil {
+			continue
+		}
+		if summary.Name == nil || summary.Id == nil {
+			continue
+		}
+		notAfter := summary.CurrentVersionSummary.Validity.TimeOfValidityNotAfter.Time
+		results = append(results, Result{
+			Endpoint:      *summary.Name,
+			DaysRemaining: int(time.Until(notAfter).Hours() / 24),
+			Target:        Target{Compartment: compartmentID, ResourceID: *summary.Id, LBName: *summary.Name},
+			Source:        SourceCertService,
+		})
+	}
+	return results, nil
+}
+
+// CheckVaultSecrets checks the Vault secrets named by secretIDs and computes days-until-expiry by parsing the
+// first PEM certificate out of each secret bundle. It does not list the secrets of a compartment: only the
+// given IDs are checked, and compartmentID is only attached to each Result as its compartment.
+func CheckVaultSecrets(ctx context.Context, compartmentID string, secretIDs []string) ([]Result, error) {
+	provider, err := auth.ResourcePrincipalConfigurationProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Resource Principal provider: %v", err)
+	}
+	client, err := secrets.NewSecretsClientWithConfigurationProvider(provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Secrets client: %v", err)
+	}
+
+	var results []Result
+	for _, secretID := range secretIDs {
+		result, err := checkVaultSecret(ctx, client, secretID, compartmentID)
+		if err != nil {
+			results = append(results, Result{Endpoint: secretID, Err: err, Target: Target{Compartment: compartmentID, ResourceID: secretID}, Source: SourceVault})
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func checkVaultSecret(ctx context.Context, client secrets.SecretsClient, secretID, compartmentID string) (Result, error) {
+	response, err := client.GetSecretBundle(ctx, secrets.GetSecretBundleRequest{SecretId: &secretID})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to get secret bundle for '%s': %v", secretID, err)
+	}
+
+	content, ok := response.SecretBundleContent.(secrets.Base64SecretBundleContentDetails)
+	if !ok || content.Content == nil {
+		return Result{}, fmt.Errorf("secret bundle '%s' does not contain base64 content", secretID)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(*content.Content)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to decode secret bundle '%s': %v", secretID, err)
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return Result{}, fmt.Errorf("secret bundle '%s' does not contain PEM data", secretID)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to parse certificate from secret '%s': %v", secretID, err)
+	}
+
+	return Result{
+		Endpoint:      secretID,
+		DaysRemaining: int(time.Until(cert.NotAfter).Hours() / 24),
+		Target:        Target{Compartment: compartmentID, ResourceID: secretID},
+		Source:        SourceVault,
+	}, nil
+}